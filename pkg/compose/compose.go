@@ -0,0 +1,285 @@
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/inspect"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// Provenance records, for each leaf key path (dot-separated, matching the path conventions
+// inspect.MergeStrategy uses), which source last wrote it. It's meant to be surfaced in
+// status so operators can tell why a given profile key ended up with a given value.
+type Provenance map[string]string
+
+// GitFetcher fetches a single file's bytes out of a git repository. It's injected rather
+// than implemented here so ComposeProfile doesn't force a git client dependency on callers
+// that never use git-backed sources.
+type GitFetcher func(ctx context.Context, ref GitRef) ([]byte, error)
+
+// ComposeProfile merges an ordered list of profile sources, lowest precedence first, into a
+// single FreeForm profile using the strategic-merge rules in inspect.MergeWithStrategy, and
+// returns a Provenance recording which source last wrote each leaf key. c is only needed
+// when sources include a configMap or secret reference; it may be nil otherwise.
+func ComposeProfile(ctx context.Context, c client.Client, sources []ProfileSource, opts ...Option) (interfaces.FreeForm, Provenance, error) {
+	o := buildOptions(opts)
+	result := interfaces.FreeForm{}
+	provenance := Provenance{}
+
+	for i, src := range sources {
+		id := src.ID
+		if id == "" {
+			id = fmt.Sprintf("%s[%d]", src.Kind, i)
+		}
+		content, err := loadSource(ctx, c, src, o)
+		if err != nil {
+			return nil, nil, fmt.Errorf("loading source %s: %v", id, err)
+		}
+		result = inspect.MergeWithStrategy(result, content, inspect.DefaultMergeStrategy)
+		recordLeaves(content, nil, id, provenance)
+	}
+	return result, provenance, nil
+}
+
+// Option configures ComposeProfile.
+type Option func(*options)
+
+type options struct {
+	gitFetcher   GitFetcher
+	httpClient   *http.Client
+	allowedFiles []string
+	allowedHTTP  []string
+}
+
+// WithGitFetcher supplies the function used to resolve git-backed sources.
+func WithGitFetcher(f GitFetcher) Option {
+	return func(o *options) { o.gitFetcher = f }
+}
+
+// WithHTTPClient overrides the client used to resolve http-backed sources.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(o *options) { o.httpClient = hc }
+}
+
+// WithAllowedFilePaths allow-lists the local path prefixes a "file" source may read from. A
+// ProfileSource's Kind/content come from a SpinnakerService CR, so without this a file source
+// lets anyone who can edit that CR read an arbitrary file on the operator pod's filesystem
+// (e.g. another container's mounted secret). File sources are rejected unless this has been
+// called with a prefix matching the requested path -- there's no default allow-list.
+func WithAllowedFilePaths(prefixes ...string) Option {
+	return func(o *options) { o.allowedFiles = append(o.allowedFiles, prefixes...) }
+}
+
+// WithAllowedHTTPHosts allow-lists the hosts an "http" source may fetch from. Without this, an
+// http source is attacker-controlled SSRF: a SpinnakerService editor could point it at an
+// internal service or a cloud metadata endpoint (e.g. 169.254.169.254) and fold the response
+// into their own profile. HTTP sources are rejected unless this has been called with a host
+// matching the requested URL -- there's no default allow-list.
+func WithAllowedHTTPHosts(hosts ...string) Option {
+	return func(o *options) { o.allowedHTTP = append(o.allowedHTTP, hosts...) }
+}
+
+func buildOptions(opts []Option) *options {
+	o := &options{httpClient: http.DefaultClient}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+func loadSource(ctx context.Context, c client.Client, src ProfileSource, o *options) (interfaces.FreeForm, error) {
+	var raw interfaces.FreeForm
+	var err error
+	switch src.Kind {
+	case KindInline:
+		raw = src.Inline
+	case KindConfigMap:
+		raw, err = loadConfigMap(ctx, c, src.ConfigMap)
+	case KindSecret:
+		raw, err = loadSecret(ctx, c, src.Secret)
+	case KindGit:
+		raw, err = loadGit(ctx, src.Git, o.gitFetcher)
+	case KindHTTP:
+		raw, err = loadHTTP(ctx, src.HTTP, o)
+	case KindFile:
+		raw, err = loadFile(src.File, o)
+	default:
+		return nil, fmt.Errorf("unknown source kind %q", src.Kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		raw = interfaces.FreeForm{}
+	}
+
+	if len(src.Vars) > 0 {
+		substituted, err := inspect.InspectStrings(raw, func(s string) (string, error) {
+			return substituteVars(s, src.Vars), nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		raw = substituted.(interfaces.FreeForm)
+	}
+	if len(src.Patches) > 0 {
+		patched, err := inspect.ApplyJSONPatch(raw, src.Patches)
+		if err != nil {
+			return nil, err
+		}
+		raw = patched
+	}
+	if len(src.MergePatch) > 0 {
+		raw = inspect.ApplyJSONMergePatch(raw, src.MergePatch)
+	}
+	return raw, nil
+}
+
+func loadConfigMap(ctx context.Context, c client.Client, ref *ObjectKeyRef) (interfaces.FreeForm, error) {
+	if ref == nil {
+		return nil, fmt.Errorf("configMap source requires a configMap reference")
+	}
+	cm := &corev1.ConfigMap{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, cm); err != nil {
+		return nil, fmt.Errorf("fetching configmap %s/%s: %v", ref.Namespace, ref.Name, err)
+	}
+	v, ok := cm.Data[ref.Key]
+	if !ok {
+		return nil, fmt.Errorf("configmap %s/%s has no key %q", ref.Namespace, ref.Name, ref.Key)
+	}
+	return parseFreeForm([]byte(v))
+}
+
+func loadSecret(ctx context.Context, c client.Client, ref *ObjectKeyRef) (interfaces.FreeForm, error) {
+	if ref == nil {
+		return nil, fmt.Errorf("secret source requires a secret reference")
+	}
+	s := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, s); err != nil {
+		return nil, fmt.Errorf("fetching secret %s/%s: %v", ref.Namespace, ref.Name, err)
+	}
+	v, ok := s.Data[ref.Key]
+	if !ok {
+		return nil, fmt.Errorf("secret %s/%s has no key %q", ref.Namespace, ref.Name, ref.Key)
+	}
+	return parseFreeForm(v)
+}
+
+func loadGit(ctx context.Context, ref *GitRef, fetch GitFetcher) (interfaces.FreeForm, error) {
+	if ref == nil {
+		return nil, fmt.Errorf("git source requires a git reference")
+	}
+	if fetch == nil {
+		return nil, fmt.Errorf("git source %s requires a GitFetcher (see WithGitFetcher)", ref.URL)
+	}
+	b, err := fetch(ctx, *ref)
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s@%s %s: %v", ref.URL, ref.Ref, ref.Path, err)
+	}
+	return parseFreeForm(b)
+}
+
+func loadHTTP(ctx context.Context, ref *HTTPRef, o *options) (interfaces.FreeForm, error) {
+	if ref == nil {
+		return nil, fmt.Errorf("http source requires a url")
+	}
+	if err := checkAllowedHTTPHost(ref.URL, o.allowedHTTP); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ref.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %v", ref.URL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("fetching %s: unexpected status %s", ref.URL, resp.Status)
+	}
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return parseFreeForm(b)
+}
+
+func loadFile(ref *FileRef, o *options) (interfaces.FreeForm, error) {
+	if ref == nil {
+		return nil, fmt.Errorf("file source requires a path")
+	}
+	if err := checkAllowedFilePath(ref.Path, o.allowedFiles); err != nil {
+		return nil, err
+	}
+	b, err := ioutil.ReadFile(ref.Path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %v", ref.Path, err)
+	}
+	return parseFreeForm(b)
+}
+
+// checkAllowedFilePath rejects a file source path unless it has one of allowed as a path
+// prefix. allowed is expected to come from WithAllowedFilePaths; an empty allow-list rejects
+// every path, since a ProfileSource's content is attacker-controlled CR data and there's no
+// safe default set of paths to read from the operator pod's filesystem.
+func checkAllowedFilePath(path string, allowed []string) error {
+	for _, prefix := range allowed {
+		if strings.HasPrefix(path, prefix) {
+			return nil
+		}
+	}
+	return fmt.Errorf("file source %q is not under an allow-listed path (see compose.WithAllowedFilePaths)", path)
+}
+
+// checkAllowedHTTPHost rejects an http source URL unless its host is one of allowed. allowed
+// is expected to come from WithAllowedHTTPHosts; an empty allow-list rejects every URL, since a
+// ProfileSource's content is attacker-controlled CR data and fetching it unrestricted is SSRF
+// (including against cloud metadata endpoints).
+func checkAllowedHTTPHost(rawURL string, allowed []string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid http source url %q: %v", rawURL, err)
+	}
+	for _, host := range allowed {
+		if u.Hostname() == host {
+			return nil
+		}
+	}
+	return fmt.Errorf("http source host %q is not allow-listed (see compose.WithAllowedHTTPHosts)", u.Hostname())
+}
+
+func parseFreeForm(b []byte) (interfaces.FreeForm, error) {
+	var ff interfaces.FreeForm
+	if err := yaml.Unmarshal(b, &ff); err != nil {
+		return nil, fmt.Errorf("parsing content: %v", err)
+	}
+	return ff, nil
+}
+
+func substituteVars(s string, vars map[string]string) string {
+	for k, v := range vars {
+		s = strings.ReplaceAll(s, "${"+k+"}", v)
+	}
+	return s
+}
+
+func recordLeaves(content map[string]interface{}, path []string, id string, out Provenance) {
+	for k, v := range content {
+		p := append(append([]string{}, path...), k)
+		if m, ok := v.(map[string]interface{}); ok {
+			recordLeaves(m, p, id, out)
+			continue
+		}
+		out[strings.Join(p, ".")] = id
+	}
+}
@@ -0,0 +1,64 @@
+package compose
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+)
+
+func TestComposeProfileSubstitutesVars(t *testing.T) {
+	src := ProfileSource{
+		ID:   "with-vars",
+		Kind: KindInline,
+		Inline: interfaces.FreeForm{
+			"greeting": "hello ${name}",
+		},
+		Vars: map[string]string{"name": "world"},
+	}
+
+	composed, provenance, err := ComposeProfile(context.Background(), nil, []ProfileSource{src})
+	if err != nil {
+		t.Fatalf("ComposeProfile returned error: %v", err)
+	}
+	if got := composed["greeting"]; got != "hello world" {
+		t.Fatalf("expected substituted greeting %q, found %q", "hello world", got)
+	}
+	if provenance["greeting"] != "with-vars" {
+		t.Fatalf("expected provenance to credit %q, found %q", "with-vars", provenance["greeting"])
+	}
+}
+
+func TestComposeProfileRejectsFileSourceWithoutAllowList(t *testing.T) {
+	src := ProfileSource{Kind: KindFile, File: &FileRef{Path: "/etc/passwd"}}
+
+	if _, _, err := ComposeProfile(context.Background(), nil, []ProfileSource{src}); err == nil {
+		t.Fatal("expected a file source to be rejected with no allow-listed paths configured")
+	}
+}
+
+func TestComposeProfileAllowsFileSourceUnderAllowedPath(t *testing.T) {
+	dir := t.TempDir()
+	path := dir + "/profile.yaml"
+	if err := os.WriteFile(path, []byte("greeting: hello\n"), 0o644); err != nil {
+		t.Fatalf("writing test fixture: %v", err)
+	}
+	src := ProfileSource{Kind: KindFile, File: &FileRef{Path: path}}
+
+	composed, _, err := ComposeProfile(context.Background(), nil, []ProfileSource{src}, WithAllowedFilePaths(dir))
+	if err != nil {
+		t.Fatalf("ComposeProfile returned error: %v", err)
+	}
+	if composed["greeting"] != "hello" {
+		t.Fatalf("expected greeting %q, found %v", "hello", composed["greeting"])
+	}
+}
+
+func TestComposeProfileRejectsHTTPSourceWithoutAllowList(t *testing.T) {
+	src := ProfileSource{Kind: KindHTTP, HTTP: &HTTPRef{URL: "http://169.254.169.254/latest/meta-data/"}}
+
+	if _, _, err := ComposeProfile(context.Background(), nil, []ProfileSource{src}); err == nil {
+		t.Fatal("expected an http source to be rejected with no allow-listed hosts configured")
+	}
+}
@@ -0,0 +1,80 @@
+// Package compose builds a single profile out of an ordered list of layered sources --
+// inline maps, ConfigMaps, Secrets, git files, HTTP documents, or local files -- composed
+// kustomize-style: later sources take precedence, each may carry its own patches, and the
+// result tracks which source last wrote each key.
+package compose
+
+import (
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/inspect"
+)
+
+// SourceKind identifies where a ProfileSource's content comes from.
+type SourceKind string
+
+const (
+	KindInline    SourceKind = "inline"
+	KindConfigMap SourceKind = "configMap"
+	KindSecret    SourceKind = "secret"
+	KindGit       SourceKind = "git"
+	KindHTTP      SourceKind = "http"
+	KindFile      SourceKind = "file"
+)
+
+// ProfileSource is one layer in a layered profile composition. Sources are composed in the
+// order given, lowest precedence first; exactly one of Inline/ConfigMap/Secret/Git/HTTP/File
+// should be set, matching Kind.
+type ProfileSource struct {
+	// ID identifies this source in the returned Provenance. Defaults to "<kind>[<index>]"
+	// when empty.
+	ID   string     `json:"id,omitempty"`
+	Kind SourceKind `json:"kind"`
+
+	Inline    interfaces.FreeForm `json:"inline,omitempty"`
+	ConfigMap *ObjectKeyRef       `json:"configMap,omitempty"`
+	Secret    *ObjectKeyRef       `json:"secret,omitempty"`
+	Git       *GitRef             `json:"git,omitempty"`
+	HTTP      *HTTPRef            `json:"http,omitempty"`
+	File      *FileRef            `json:"file,omitempty"`
+
+	// Patches is applied, in order, after this source's raw content is loaded and before
+	// it's merged into the running composition.
+	Patches []inspect.Operation `json:"patches,omitempty"`
+	// MergePatch is applied after Patches, using RFC 7396 JSON Merge Patch semantics.
+	MergePatch map[string]interface{} `json:"mergePatch,omitempty"`
+	// Vars substitutes "${name}" placeholders found in any string in this source's content,
+	// resolved before Patches/MergePatch are applied.
+	Vars map[string]string `json:"vars,omitempty"`
+}
+
+// ObjectKeyRef points at a single key within a Kubernetes ConfigMap or Secret, whose value
+// is parsed as YAML or JSON to produce a source's content.
+type ObjectKeyRef struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Key       string `json:"key"`
+}
+
+// GitRef points at a YAML or JSON file at Path within a git repository.
+type GitRef struct {
+	URL  string `json:"url"`
+	Ref  string `json:"ref,omitempty"`
+	Path string `json:"path"`
+}
+
+// HTTPRef points at a YAML or JSON document served over HTTP(S).
+type HTTPRef struct {
+	URL string `json:"url"`
+}
+
+// FileRef points at a YAML or JSON file on disk, local to the operator pod.
+type FileRef struct {
+	Path string `json:"path"`
+}
+
+// InlineSource is a convenience constructor for an in-memory ProfileSource, used both by
+// callers composing a profile from Go and by defaultsTransformer to fold its own output in
+// as the implicit lowest-precedence layer.
+func InlineSource(id string, content interfaces.FreeForm) ProfileSource {
+	return ProfileSource{ID: id, Kind: KindInline, Inline: content}
+}
@@ -3,20 +3,64 @@ package transformer
 import (
 	"context"
 	"fmt"
+	"sync"
+
 	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
 	"github.com/armory/spinnaker-operator/pkg/bom"
+	"github.com/armory/spinnaker-operator/pkg/compose"
 	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/inspect"
+	"github.com/armory/spinnaker-operator/pkg/secrets"
 	"k8s.io/apimachinery/pkg/runtime"
 
 	"github.com/go-logr/logr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// registerSecretResolversOnce registers the built-in secret resolvers (see
+// secrets.RegisterDefaults) the first time a defaultsTransformer is constructed, using the
+// controller-runtime client already available here for the k8s:// resolver. Vault/GCP/AWS
+// clients aren't threaded through to transformer generators yet, so those resolvers are
+// registered with a nil backing client and will error if a ref actually resolves through them
+// until that wiring exists.
+var registerSecretResolversOnce sync.Once
+
+// patchesKey is the reserved profile key under which users may declare a kustomize-style
+// overlay as a list of RFC 6902 JSON Patch operations. It's stripped from the profile once
+// applied so it never reaches the rendered Spinnaker config.
+const patchesKey = "$patches"
+
+// sourcesKey is the reserved profile key under which users may declare additional layered
+// profile sources (ConfigMaps, Secrets, git/http/local files) to compose on top of this
+// profile. The profile itself -- with defaults and patches already applied -- becomes the
+// implicit, lowest-precedence source in the composition.
+const sourcesKey = "$sources"
+
+// ProvenanceRecorder is implemented by interfaces.SpinnakerService when it can surface
+// per-profile compose.Provenance in its status, e.g. as a condition or a dedicated status
+// field. Implementing it is optional: none of the concrete SpinnakerService CRD types in
+// this tree do yet (that code lives outside this package), so until one does, Provenance
+// below is the only way to read a profile's provenance back out short of grepping logs.
+type ProvenanceRecorder interface {
+	RecordProfileProvenance(profileName string, provenance compose.Provenance)
+}
+
 // defaultsTransformer inserts default values to *-local profile to each service
 type defaultsTransformer struct {
-	svc    interfaces.SpinnakerService
-	log    logr.Logger
-	client client.Client
+	svc        interfaces.SpinnakerService
+	log        logr.Logger
+	client     client.Client
+	provenance map[string]compose.Provenance
+}
+
+// Provenance returns the compose.Provenance recorded for profileName by the most recent
+// TransformConfig call. Unlike ProvenanceRecorder, this doesn't depend on svc implementing
+// anything -- it's populated for every profile that declares a sourcesKey entry, whether or
+// not svc implements ProvenanceRecorder, so provenance is retrievable even for SpinnakerService
+// types that haven't added status support yet. Profiles without sourcesKey get no entry.
+func (a *defaultsTransformer) Provenance(profileName string) (compose.Provenance, bool) {
+	p, ok := a.provenance[profileName]
+	return p, ok
 }
 
 type defaultsTransformerGenerator struct{}
@@ -29,13 +73,16 @@ func (a *defaultsTransformerGenerator) NewTransformer(
 	svc interfaces.SpinnakerService,
 	client client.Client,
 	log logr.Logger) (Transformer, error) {
-	return &defaultsTransformer{svc: svc, log: log, client: client}, nil
+	registerSecretResolversOnce.Do(func() {
+		secrets.RegisterDefaults(client, nil, nil, nil)
+	})
+	return &defaultsTransformer{svc: svc, log: log, client: client, provenance: map[string]compose.Provenance{}}, nil
 }
 
 func (a *defaultsTransformer) TransformConfig(ctx context.Context) error {
 	err := a.setArchaiusDefaults(ctx)
 	if err != nil {
-		return fmt.Errorf("error while setting Archaius: %e", err)
+		return fmt.Errorf("error while setting Archaius: %v", err)
 	}
 	return nil
 }
@@ -46,12 +93,109 @@ func (a *defaultsTransformer) setArchaiusDefaults(ctx context.Context) error {
 		p := a.assertProfile(config, profileName)
 		err := a.setArchaiusDefaultsForProfile(p, profileName)
 		if err != nil {
-			return fmt.Errorf("error while handling profile %s: %e", profileName, err)
+			return fmt.Errorf("error while handling profile %s: %v", profileName, err)
+		}
+		p, err = a.applyDeclaredPatches(p)
+		if err != nil {
+			return fmt.Errorf("error while applying patches for profile %s: %v", profileName, err)
 		}
+		p, err = a.applyDeclaredSources(ctx, p, profileName)
+		if err != nil {
+			return fmt.Errorf("error while composing sources for profile %s: %v", profileName, err)
+		}
+		p, err = a.resolveSecrets(ctx, p, profileName)
+		if err != nil {
+			return fmt.Errorf("error while resolving secret references for profile %s: %v", profileName, err)
+		}
+		config.Profiles[profileName] = p
 	}
 	return nil
 }
 
+// applyDeclaredSources looks for a sourcesKey entry in profile and, if present, composes it
+// on top of profile using pkg/compose, then strips the key from the result.
+//
+// No compose.WithAllowedFilePaths/WithAllowedHTTPHosts are passed here, so $sources entries of
+// kind "file" or "http" -- both attacker-controlled CR content -- are rejected outright rather
+// than reading an arbitrary path on the operator pod or fetching an arbitrary URL (SSRF) on its
+// behalf. There's no operator-wide flag surface in this tree yet to let an operator opt specific
+// paths/hosts into that; wire one through NewTransformer's generator config once one exists,
+// rather than defaulting this open.
+func (a *defaultsTransformer) applyDeclaredSources(ctx context.Context, profile interfaces.FreeForm, profileName string) (interfaces.FreeForm, error) {
+	raw, ok := profile[sourcesKey]
+	if !ok {
+		return profile, nil
+	}
+	var sources []compose.ProfileSource
+	if err := inspect.Convert(raw, &sources); err != nil {
+		return nil, fmt.Errorf("invalid %s entry: %v", sourcesKey, err)
+	}
+	delete(profile, sourcesKey)
+
+	all := append([]compose.ProfileSource{compose.InlineSource("profile:"+profileName, profile)}, sources...)
+	composed, provenance, err := compose.ComposeProfile(ctx, a.client, all)
+	if err != nil {
+		return nil, err
+	}
+	a.provenance[profileName] = provenance
+	if pr, ok := a.svc.(ProvenanceRecorder); ok {
+		pr.RecordProfileProvenance(profileName, provenance)
+	}
+	a.log.Info("Composed profile sources", "profileName", profileName, "sources", len(sources), "keys", len(provenance))
+	return composed, nil
+}
+
+// resolveSecrets resolves any "<scheme>://..." secret reference found in a string field of
+// profile against the registered secrets.Resolver for that scheme, so profiles can reference
+// k8s/vault/gcpsm/awssm/encrypted secrets directly instead of relying on Spinnaker's own
+// halyard-side decryption.
+//
+// Scalar refs are inlined in place. File-backed refs are rejected here rather than silently
+// accepted: secrets.Resolve replaces the string field with the path the secret would be
+// mounted at, but nothing in this transformer chain actually materializes that file onto a
+// generated manifest yet (TransformManifests is a no-op below), so accepting the ref would
+// produce a profile pointing at a file that's never written anywhere. Failing the transform
+// with a clear message is preferable to shipping a profile that references a dangling path.
+func (a *defaultsTransformer) resolveSecrets(ctx context.Context, profile interfaces.FreeForm, profileName string) (interfaces.FreeForm, error) {
+	resolved, files, _, err := secrets.Resolve(ctx, profile, secrets.WithNamespace(a.svc.GetNamespace()))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) > 0 {
+		return nil, fmt.Errorf(
+			"profile %s references %d file-backed secret(s), which aren't supported yet: "+
+				"this transformer has no way to materialize them onto a generated manifest; "+
+				"use an inline-value secret reference instead until mounting is implemented",
+			profileName, len(files))
+	}
+	p, ok := resolved.(interfaces.FreeForm)
+	if !ok {
+		return nil, fmt.Errorf("expected resolving profile %s to produce a map, found %T", profileName, resolved)
+	}
+	return p, nil
+}
+
+// applyDeclaredPatches looks for a patchesKey entry in profile and, if present, applies it
+// as a sequence of JSON Patch operations and strips the key from the result. This lets users
+// express profile overrides declaratively in the SpinnakerService CR instead of hand-mutating
+// the generated halyard config.
+func (a *defaultsTransformer) applyDeclaredPatches(profile interfaces.FreeForm) (interfaces.FreeForm, error) {
+	raw, ok := profile[patchesKey]
+	if !ok {
+		return profile, nil
+	}
+	var ops []inspect.Operation
+	if err := inspect.Convert(raw, &ops); err != nil {
+		return nil, fmt.Errorf("invalid %s entry: %v", patchesKey, err)
+	}
+	delete(profile, patchesKey)
+	patched, err := inspect.ApplyJSONPatch(profile, ops)
+	if err != nil {
+		return nil, err
+	}
+	return patched, nil
+}
+
 func (a *defaultsTransformer) setArchaiusDefaultsForProfile(profile interfaces.FreeForm, profileName string) error {
 	var ok bool
 	archaius_, ok := profile["archaius"]
@@ -0,0 +1,82 @@
+package transformer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/bom"
+	"github.com/armory/spinnaker-operator/pkg/generated"
+	"github.com/armory/spinnaker-operator/pkg/inspect"
+	"github.com/armory/spinnaker-operator/pkg/schema"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// schemaRegistry is populated once from the schemas embedded in the binary. Swapping it for
+// a registry seeded via Registry.RefreshFromBOM is how newly released Spinnaker versions would
+// pick up schema validation without an operator rebuild.
+var schemaRegistry, schemaRegistryErr = schema.NewRegistry()
+
+// schemaTransformer validates every profile's merged content against the JSON Schema shipped
+// for that service before any other transformer runs (see Generators), so profile mistakes
+// surface as a clear, per-field error instead of failing late and opaquely deep in
+// reconciliation.
+//
+// Schemas are currently looked up unversioned (see schema.Registry.Get): resolving the exact
+// BOM version a SpinnakerService requested requires plumbing version information out of
+// SpinnakerConfig, which isn't wired up yet. The embedded assets also only model a handful of
+// known top-level keys per service today, deliberately without "additionalProperties": false
+// at the root: real profiles use plenty of per-provider and per-account shapes this package
+// doesn't model yet, and failing closed on those would reject valid profiles. Generating the
+// schemas (and corresponding typed Go structs for ConvertStrict) from Halyard's own schema
+// sources instead of hand-authoring them is tracked as follow-up work, not done here.
+type schemaTransformer struct {
+	svc interfaces.SpinnakerService
+	log logr.Logger
+}
+
+type schemaTransformerGenerator struct{}
+
+func (g *schemaTransformerGenerator) GetName() string {
+	return "Schema"
+}
+
+func (g *schemaTransformerGenerator) NewTransformer(
+	svc interfaces.SpinnakerService,
+	client client.Client,
+	log logr.Logger) (Transformer, error) {
+	return &schemaTransformer{svc: svc, log: log}, nil
+}
+
+func (a *schemaTransformer) TransformConfig(ctx context.Context) error {
+	if schemaRegistryErr != nil || schemaRegistry == nil {
+		a.log.Info("Schema validation skipped: embedded schemas failed to load", "error", schemaRegistryErr)
+		return nil
+	}
+
+	config := a.svc.GetSpinnakerConfig()
+	var diags []inspect.Diagnostic
+	for _, profileName := range bom.JavaServices() {
+		p, ok := config.Profiles[profileName]
+		if !ok {
+			continue
+		}
+		s, ok := schemaRegistry.Get(profileName, "")
+		if !ok {
+			continue // no schema shipped for this service yet
+		}
+		for _, d := range schema.Validate(s, p) {
+			diags = append(diags, inspect.Diagnostic{Path: "/" + profileName + d.Path, Message: d.Message})
+		}
+	}
+	if len(diags) > 0 {
+		return fmt.Errorf("profile validation failed: %v", diags)
+	}
+	return nil
+}
+
+func (a *schemaTransformer) TransformManifests(ctx context.Context, scheme *runtime.Scheme, gen *generated.SpinnakerGeneratedConfig) error {
+	return nil // noop
+}
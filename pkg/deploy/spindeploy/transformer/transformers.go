@@ -0,0 +1,10 @@
+package transformer
+
+// Generators lists every TransformerGenerator the reconciler's transform pipeline
+// constructs, in run order. Schema runs first so a malformed profile fails fast with a
+// precise per-field diagnostic before Defaults or any later transformer has a chance to
+// propagate it deeper into reconciliation.
+var Generators = []TransformerGenerator{
+	&schemaTransformerGenerator{},
+	&defaultsTransformerGenerator{},
+}
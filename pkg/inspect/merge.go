@@ -0,0 +1,130 @@
+package inspect
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MergeStrategy controls how Merge resolves slices of keyed objects (accounts, providers,
+// custom resources, ...) instead of blindly concatenating them. Without a strategy, merging
+// two profiles that both define e.g. an account named "prod" produces two "prod" entries.
+type MergeStrategy struct {
+	// Keys maps a dot-separated path from the document root (e.g. "accounts" or
+	// "providers.kubernetes.accounts") to the field name slice elements at that path are
+	// keyed by, mirroring Kubernetes' patchMergeKey convention.
+	Keys map[string]string
+}
+
+// DefaultMergeStrategy holds the merge keys known for well-known Spinnaker config sections.
+var DefaultMergeStrategy = MergeStrategy{
+	Keys: map[string]string{
+		"accounts":        "name",
+		"customResources": "kubernetesKind",
+		"providers":       "name",
+	},
+}
+
+// MergeWithStrategy behaves like Merge but applies strategic-merge semantics to slices:
+// a slice found at a path registered in strategy.Keys is merged element-by-element by
+// matching the configured key field rather than concatenated. Slices at unregistered
+// paths, or slices whose elements aren't keyed objects, fall back to Merge's behavior.
+func MergeWithStrategy(a, b map[string]interface{}, strategy MergeStrategy) map[string]interface{} {
+	return mergeWithStrategy(a, b, nil, strategy)
+}
+
+func mergeWithStrategy(a, b map[string]interface{}, path []string, strategy MergeStrategy) map[string]interface{} {
+	result := make(map[string]interface{})
+	for k, av := range a {
+		p := append(append([]string{}, path...), k)
+		if avm, ok := av.(map[string]interface{}); ok {
+			if bv, ok := b[k]; ok {
+				if bvm, ok := bv.(map[string]interface{}); ok {
+					result[k] = mergeWithStrategy(avm, bvm, p, strategy)
+					continue
+				}
+				result[k] = bv
+				continue
+			}
+			result[k] = av
+			continue
+		}
+		if ar, ok := av.([]interface{}); ok {
+			if bv, ok := b[k]; ok {
+				if br, ok := bv.([]interface{}); ok {
+					result[k] = mergeSliceWithStrategy(ar, br, p, strategy)
+					continue
+				}
+				result[k] = bv
+				continue
+			}
+			result[k] = av
+			continue
+		}
+		if bv, ok := b[k]; ok {
+			result[k] = bv
+		} else {
+			result[k] = av
+		}
+	}
+	for k, bv := range b {
+		if _, ok := a[k]; !ok {
+			result[k] = bv
+		}
+	}
+	return result
+}
+
+// mergeSliceWithStrategy merges two slices found at path. If path carries a merge key and
+// every element on both sides is a keyed object, elements are matched by key: entries
+// present in both are merged recursively, entries only in b are appended, and relative
+// order from a is preserved. Otherwise it concatenates, same as Merge.
+func mergeSliceWithStrategy(a, b []interface{}, path []string, strategy MergeStrategy) interface{} {
+	key := strategy.keyFor(path)
+	if key == "" {
+		return concatSlices(a, b)
+	}
+
+	order := make([]string, 0, len(a))
+	byKey := make(map[string]map[string]interface{}, len(a))
+	for _, item := range a {
+		im, ok := item.(map[string]interface{})
+		if !ok || im[key] == nil {
+			return concatSlices(a, b)
+		}
+		kv := fmt.Sprintf("%v", im[key])
+		order = append(order, kv)
+		byKey[kv] = im
+	}
+	for _, item := range b {
+		im, ok := item.(map[string]interface{})
+		if !ok || im[key] == nil {
+			return concatSlices(a, b)
+		}
+		kv := fmt.Sprintf("%v", im[key])
+		if existing, ok := byKey[kv]; ok {
+			byKey[kv] = mergeWithStrategy(existing, im, path, strategy)
+		} else {
+			order = append(order, kv)
+			byKey[kv] = im
+		}
+	}
+	result := make([]interface{}, 0, len(order))
+	for _, kv := range order {
+		result = append(result, byKey[kv])
+	}
+	return result
+}
+
+func concatSlices(a, b []interface{}) []interface{} {
+	combined := make([]interface{}, 0, len(a)+len(b))
+	combined = append(combined, a...)
+	combined = append(combined, b...)
+	return combined
+}
+
+func (s MergeStrategy) keyFor(path []string) string {
+	if s.Keys == nil {
+		return ""
+	}
+	return s.Keys[strings.Join(path, ".")]
+}
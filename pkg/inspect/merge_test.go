@@ -0,0 +1,50 @@
+package inspect
+
+import "testing"
+
+func TestMergeWithStrategyMatchesSliceElementsByKey(t *testing.T) {
+	a := map[string]interface{}{
+		"accounts": []interface{}{
+			map[string]interface{}{"name": "prod", "region": "us-east-1"},
+			map[string]interface{}{"name": "staging", "region": "us-west-2"},
+		},
+	}
+	b := map[string]interface{}{
+		"accounts": []interface{}{
+			map[string]interface{}{"name": "prod", "region": "us-east-2"},
+			map[string]interface{}{"name": "dev", "region": "us-west-1"},
+		},
+	}
+
+	merged := MergeWithStrategy(a, b, DefaultMergeStrategy)
+	accounts := merged["accounts"].([]interface{})
+	if len(accounts) != 3 {
+		t.Fatalf("expected 3 accounts (prod merged, staging kept, dev added), found %d: %v", len(accounts), accounts)
+	}
+	prod := accounts[0].(map[string]interface{})
+	if prod["region"] != "us-east-2" {
+		t.Fatalf("expected b's region to win for the matched prod account, found %v", prod["region"])
+	}
+}
+
+func TestMergeWithStrategyFallsBackToConcatForUnkeyedPaths(t *testing.T) {
+	a := map[string]interface{}{"tags": []interface{}{"a"}}
+	b := map[string]interface{}{"tags": []interface{}{"b"}}
+
+	merged := MergeWithStrategy(a, b, DefaultMergeStrategy)
+	tags := merged["tags"].([]interface{})
+	if len(tags) != 2 {
+		t.Fatalf("expected unregistered slice path to concatenate, found %v", tags)
+	}
+}
+
+func TestMergeWithStrategyFallsBackForNonObjectElements(t *testing.T) {
+	a := map[string]interface{}{"accounts": []interface{}{"prod"}}
+	b := map[string]interface{}{"accounts": []interface{}{"staging"}}
+
+	merged := MergeWithStrategy(a, b, DefaultMergeStrategy)
+	accounts := merged["accounts"].([]interface{})
+	if len(accounts) != 2 {
+		t.Fatalf("expected non-object slice elements to concatenate rather than key-match, found %v", accounts)
+	}
+}
@@ -0,0 +1,312 @@
+package inspect
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Operation is a single RFC 6902 JSON Patch operation using RFC 6901 JSON Pointer paths.
+type Operation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	From  string      `json:"from,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// ApplyJSONPatch applies a sequence of RFC 6902 operations (add/remove/replace/move/copy/test)
+// to profile and returns the result. profile itself is left untouched; operations are applied
+// to a deep copy produced via a JSON round-trip, same approach Convert uses elsewhere.
+func ApplyJSONPatch(profile map[string]interface{}, ops []Operation) (map[string]interface{}, error) {
+	doc, err := deepCopyFreeForm(profile)
+	if err != nil {
+		return nil, err
+	}
+	var root interface{} = doc
+	for _, op := range ops {
+		root, err = applyOp(root, op)
+		if err != nil {
+			return nil, fmt.Errorf("error applying %s at %q: %v", op.Op, op.Path, err)
+		}
+	}
+	result, ok := root.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("patched document is no longer an object")
+	}
+	return result, nil
+}
+
+// ApplyJSONMergePatch applies an RFC 7396 JSON Merge Patch to profile: keys in patch override
+// keys in profile, nested objects are merged recursively, and a null value removes the key.
+func ApplyJSONMergePatch(profile map[string]interface{}, patch map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(profile))
+	for k, v := range profile {
+		result[k] = v
+	}
+	for k, pv := range patch {
+		if pv == nil {
+			delete(result, k)
+			continue
+		}
+		pvm, pvIsMap := pv.(map[string]interface{})
+		if !pvIsMap {
+			result[k] = pv
+			continue
+		}
+		if existing, ok := result[k].(map[string]interface{}); ok {
+			result[k] = ApplyJSONMergePatch(existing, pvm)
+		} else {
+			result[k] = ApplyJSONMergePatch(map[string]interface{}{}, pvm)
+		}
+	}
+	return result
+}
+
+func applyOp(root interface{}, op Operation) (interface{}, error) {
+	switch op.Op {
+	case "add":
+		return setPointer(root, op.Path, op.Value, true)
+	case "replace":
+		return setPointer(root, op.Path, op.Value, false)
+	case "remove":
+		return removePointer(root, op.Path)
+	case "move":
+		v, err := getPointer(root, op.From)
+		if err != nil {
+			return nil, err
+		}
+		root, err = removePointer(root, op.From)
+		if err != nil {
+			return nil, err
+		}
+		return setPointer(root, op.Path, v, true)
+	case "copy":
+		v, err := getPointer(root, op.From)
+		if err != nil {
+			return nil, err
+		}
+		v, err = deepCopyValue(v)
+		if err != nil {
+			return nil, err
+		}
+		return setPointer(root, op.Path, v, true)
+	case "test":
+		v, err := getPointer(root, op.Path)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(v, op.Value) {
+			return nil, fmt.Errorf("test failed: expected %v, found %v", op.Value, v)
+		}
+		return root, nil
+	default:
+		return nil, fmt.Errorf("unsupported operation %q", op.Op)
+	}
+}
+
+func parsePointer(path string) ([]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(path, "/") {
+		return nil, fmt.Errorf("JSON pointer must start with '/', found %q", path)
+	}
+	tokens := strings.Split(path[1:], "/")
+	for i, t := range tokens {
+		t = strings.ReplaceAll(t, "~1", "/")
+		t = strings.ReplaceAll(t, "~0", "~")
+		tokens[i] = t
+	}
+	return tokens, nil
+}
+
+func getPointer(root interface{}, path string) (interface{}, error) {
+	tokens, err := parsePointer(path)
+	if err != nil {
+		return nil, err
+	}
+	cur := root
+	for _, t := range tokens {
+		switch c := cur.(type) {
+		case map[string]interface{}:
+			v, ok := c[t]
+			if !ok {
+				return nil, fmt.Errorf("member %q not found", t)
+			}
+			cur = v
+		case []interface{}:
+			idx, err := arrayIndex(t, len(c))
+			if err != nil {
+				return nil, err
+			}
+			cur = c[idx]
+		default:
+			return nil, fmt.Errorf("cannot index into %T", cur)
+		}
+	}
+	return cur, nil
+}
+
+func setPointer(root interface{}, path string, value interface{}, insert bool) (interface{}, error) {
+	tokens, err := parsePointer(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	return setAt(root, tokens, value, insert)
+}
+
+func setAt(node interface{}, tokens []string, value interface{}, insert bool) (interface{}, error) {
+	token := tokens[0]
+	switch c := node.(type) {
+	case map[string]interface{}:
+		if len(tokens) == 1 {
+			if _, exists := c[token]; !exists && !insert {
+				return nil, fmt.Errorf("member %q not found", token)
+			}
+			c[token] = value
+			return c, nil
+		}
+		child, ok := c[token]
+		if !ok {
+			return nil, fmt.Errorf("member %q not found", token)
+		}
+		updated, err := setAt(child, tokens[1:], value, insert)
+		if err != nil {
+			return nil, err
+		}
+		c[token] = updated
+		return c, nil
+	case []interface{}:
+		if token == "-" {
+			if len(tokens) != 1 {
+				return nil, fmt.Errorf("'-' is only valid as the final pointer token")
+			}
+			return append(c, value), nil
+		}
+		bound := len(c)
+		if insert && len(tokens) == 1 {
+			// RFC 6902 allows "add" at an index equal to the array's current length, the
+			// equivalent of "-", to insert at a specific trailing position.
+			bound++
+		}
+		idx, err := arrayIndex(token, bound)
+		if err != nil {
+			return nil, err
+		}
+		if len(tokens) == 1 {
+			if insert {
+				c = append(c, nil)
+				copy(c[idx+1:], c[idx:])
+				c[idx] = value
+				return c, nil
+			}
+			c[idx] = value
+			return c, nil
+		}
+		updated, err := setAt(c[idx], tokens[1:], value, insert)
+		if err != nil {
+			return nil, err
+		}
+		c[idx] = updated
+		return c, nil
+	default:
+		return nil, fmt.Errorf("cannot index into %T", node)
+	}
+}
+
+func removePointer(root interface{}, path string) (interface{}, error) {
+	tokens, err := parsePointer(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("cannot remove document root")
+	}
+	return removeAt(root, tokens)
+}
+
+func removeAt(node interface{}, tokens []string) (interface{}, error) {
+	token := tokens[0]
+	switch c := node.(type) {
+	case map[string]interface{}:
+		if len(tokens) == 1 {
+			if _, ok := c[token]; !ok {
+				return nil, fmt.Errorf("member %q not found", token)
+			}
+			delete(c, token)
+			return c, nil
+		}
+		child, ok := c[token]
+		if !ok {
+			return nil, fmt.Errorf("member %q not found", token)
+		}
+		updated, err := removeAt(child, tokens[1:])
+		if err != nil {
+			return nil, err
+		}
+		c[token] = updated
+		return c, nil
+	case []interface{}:
+		idx, err := arrayIndex(token, len(c))
+		if err != nil {
+			return nil, err
+		}
+		if len(tokens) == 1 {
+			return append(c[:idx], c[idx+1:]...), nil
+		}
+		updated, err := removeAt(c[idx], tokens[1:])
+		if err != nil {
+			return nil, err
+		}
+		c[idx] = updated
+		return c, nil
+	default:
+		return nil, fmt.Errorf("cannot index into %T", node)
+	}
+}
+
+func arrayIndex(token string, length int) (int, error) {
+	idx, err := strconv.Atoi(token)
+	if err != nil {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	if idx < 0 || idx >= length {
+		return 0, fmt.Errorf("array index %d out of bounds", idx)
+	}
+	return idx, nil
+}
+
+// deepCopyFreeForm returns a deep copy of a FreeForm-shaped map via a JSON round-trip so
+// patch operations never mutate the caller's profile in place.
+func deepCopyFreeForm(m map[string]interface{}) (map[string]interface{}, error) {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	var copy map[string]interface{}
+	if err := json.Unmarshal(b, &copy); err != nil {
+		return nil, err
+	}
+	return copy, nil
+}
+
+// deepCopyValue deep-copies a single value of any shape (object, array, or scalar) reachable
+// at a JSON pointer via a JSON round-trip. Used by the "copy" operation so the copied location
+// is a fresh value, as RFC 6902 requires, rather than an alias onto the same map/slice as the
+// source -- otherwise a later operation under the source path would also mutate the copy.
+func deepCopyValue(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var copy interface{}
+	if err := json.Unmarshal(b, &copy); err != nil {
+		return nil, err
+	}
+	return copy, nil
+}
@@ -0,0 +1,106 @@
+package inspect
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestApplyJSONPatchAddAppendsAtArrayLength(t *testing.T) {
+	profile := map[string]interface{}{"list": []interface{}{"a", "b"}}
+	ops := []Operation{{Op: "add", Path: "/list/2", Value: "c"}}
+
+	patched, err := ApplyJSONPatch(profile, ops)
+	if err != nil {
+		t.Fatalf("ApplyJSONPatch returned error: %v", err)
+	}
+	want := []interface{}{"a", "b", "c"}
+	if got := patched["list"]; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected list %v, found %v", want, got)
+	}
+}
+
+func TestApplyJSONPatchAddOutOfBoundsStillRejected(t *testing.T) {
+	profile := map[string]interface{}{"list": []interface{}{"a", "b"}}
+	ops := []Operation{{Op: "add", Path: "/list/3", Value: "c"}}
+
+	if _, err := ApplyJSONPatch(profile, ops); err == nil {
+		t.Fatalf("expected an out-of-bounds add to error")
+	}
+}
+
+func TestApplyJSONPatchReplaceAtArrayLengthRejected(t *testing.T) {
+	profile := map[string]interface{}{"list": []interface{}{"a", "b"}}
+	ops := []Operation{{Op: "replace", Path: "/list/2", Value: "c"}}
+
+	if _, err := ApplyJSONPatch(profile, ops); err == nil {
+		t.Fatalf("expected replace at array length to error, since replace doesn't insert")
+	}
+}
+
+func TestApplyJSONPatchRemove(t *testing.T) {
+	profile := map[string]interface{}{"archaius": map[string]interface{}{"enabled": true}}
+	ops := []Operation{{Op: "remove", Path: "/archaius/enabled"}}
+
+	patched, err := ApplyJSONPatch(profile, ops)
+	if err != nil {
+		t.Fatalf("ApplyJSONPatch returned error: %v", err)
+	}
+	if _, ok := patched["archaius"].(map[string]interface{})["enabled"]; ok {
+		t.Fatalf("expected enabled to be removed, found %v", patched["archaius"])
+	}
+}
+
+func TestApplyJSONPatchLeavesOriginalUntouched(t *testing.T) {
+	profile := map[string]interface{}{"list": []interface{}{"a", "b"}}
+	ops := []Operation{{Op: "add", Path: "/list/2", Value: "c"}}
+
+	if _, err := ApplyJSONPatch(profile, ops); err != nil {
+		t.Fatalf("ApplyJSONPatch returned error: %v", err)
+	}
+	want := []interface{}{"a", "b"}
+	if got := profile["list"]; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected original profile list untouched, found %v", got)
+	}
+}
+
+func TestApplyJSONPatchCopyIsIndependentOfSource(t *testing.T) {
+	profile := map[string]interface{}{
+		"a": map[string]interface{}{"x": "orig"},
+	}
+	ops := []Operation{
+		{Op: "copy", From: "/a", Path: "/b"},
+		{Op: "replace", Path: "/a/x", Value: "changed"},
+	}
+
+	patched, err := ApplyJSONPatch(profile, ops)
+	if err != nil {
+		t.Fatalf("ApplyJSONPatch returned error: %v", err)
+	}
+	b := patched["b"].(map[string]interface{})
+	if b["x"] != "orig" {
+		t.Fatalf("expected the copy to be unaffected by a later replace under the source path, found %v", b["x"])
+	}
+}
+
+func TestApplyJSONMergePatchMergesNestedObjects(t *testing.T) {
+	profile := map[string]interface{}{
+		"archaius": map[string]interface{}{"enabled": true, "other": "keep"},
+		"dropMe":   "value",
+	}
+	patch := map[string]interface{}{
+		"archaius": map[string]interface{}{"enabled": false},
+		"dropMe":   nil,
+	}
+
+	merged := ApplyJSONMergePatch(profile, patch)
+	archaius := merged["archaius"].(map[string]interface{})
+	if archaius["enabled"] != false {
+		t.Fatalf("expected enabled to be overridden to false, found %v", archaius["enabled"])
+	}
+	if archaius["other"] != "keep" {
+		t.Fatalf("expected untouched nested key to survive merge, found %v", archaius["other"])
+	}
+	if _, ok := merged["dropMe"]; ok {
+		t.Fatalf("expected a null patch value to delete the key")
+	}
+}
@@ -0,0 +1,150 @@
+package inspect
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Diagnostic is one strict-conversion or schema-validation problem, located by a JSON
+// Pointer path into the document being checked.
+type Diagnostic struct {
+	Path    string
+	Message string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s", d.Path, d.Message)
+}
+
+// ConvertOptions configures ConvertStrict.
+type ConvertOptions struct {
+	// CoerceScalarToSlice wraps a scalar found where i2 expects a slice into a one-element
+	// slice instead of failing, matching a quirk common in hand-written Halyard YAML (e.g. a
+	// single string where a list of strings is expected).
+	CoerceScalarToSlice bool
+}
+
+// ConvertStrict behaves like Convert but rejects unknown fields instead of silently dropping
+// them. It reports every top-level unknown field it finds rather than bailing out on the
+// first one, so callers can show a more complete list of what's wrong with a profile instead
+// of forcing users through one-error-at-a-time fixes; a single type-mismatch diagnostic from
+// the underlying decoder, when present, is appended last since the standard decoder itself
+// stops at its first such error. A nil diagnostic slice with a nil error means i2 was
+// populated successfully.
+func ConvertStrict(i1 interface{}, i2 interface{}, opts ConvertOptions) ([]Diagnostic, error) {
+	b, err := json.Marshal(i1)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.CoerceScalarToSlice {
+		var raw map[string]interface{}
+		if err := json.Unmarshal(b, &raw); err == nil {
+			coerceScalarsToSlices(raw, reflect.TypeOf(i2))
+			if b, err = json.Marshal(raw); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var diags []Diagnostic
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err == nil {
+		for _, f := range unknownTopLevelFields(raw, reflect.TypeOf(i2)) {
+			diags = append(diags, Diagnostic{Path: "/" + f, Message: fmt.Sprintf("unknown field %q", f)})
+		}
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	err = dec.Decode(i2)
+	if err != nil && duplicatesUnknownField(err, diags) {
+		// The decoder keeps only its first error, so an unknown field that comes before a type
+		// mismatch hides it; decode again without the unknown-field check to find one.
+		err = json.Unmarshal(b, i2)
+	}
+	if err != nil {
+		diags = append(diags, Diagnostic{Path: "/", Message: err.Error()})
+	}
+	return diags, nil
+}
+
+// duplicatesUnknownField reports whether err is the stdlib decoder's own
+// `json: unknown field "x"` error for a field already reported in diags via
+// unknownTopLevelFields. DisallowUnknownFields stops at the first unknown field it hits, so for
+// a document with only unknown-field problems this is always the same field unknownTopLevelFields
+// already found; without this check ConvertStrict would report it twice.
+func duplicatesUnknownField(err error, diags []Diagnostic) bool {
+	msg := err.Error()
+	if !strings.Contains(msg, "unknown field") {
+		return false
+	}
+	for _, d := range diags {
+		if strings.Contains(msg, fmt.Sprintf("%q", strings.TrimPrefix(d.Path, "/"))) {
+			return true
+		}
+	}
+	return false
+}
+
+// unknownTopLevelFields returns the keys of raw that have no corresponding json-tagged field
+// on t, so ConvertStrict can report all of them instead of only the first one
+// DisallowUnknownFields happens to hit.
+func unknownTopLevelFields(raw map[string]interface{}, t reflect.Type) []string {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	known := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		tag, ok := t.Field(i).Tag.Lookup("json")
+		if !ok {
+			continue
+		}
+		known[strings.Split(tag, ",")[0]] = true
+	}
+	var unknown []string
+	for k := range raw {
+		if !known[k] {
+			unknown = append(unknown, k)
+		}
+	}
+	return unknown
+}
+
+// coerceScalarsToSlices walks raw alongside t's json-tagged fields, one level of nesting at a
+// time, and wraps any scalar found where t expects a slice into a one-element slice.
+func coerceScalarsToSlices(raw map[string]interface{}, t reflect.Type) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, ok := f.Tag.Lookup("json")
+		if !ok {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		v, ok := raw[name]
+		if !ok {
+			continue
+		}
+		if f.Type.Kind() == reflect.Slice {
+			if _, isSlice := v.([]interface{}); !isSlice {
+				raw[name] = []interface{}{v}
+			}
+			continue
+		}
+		if m, ok := v.(map[string]interface{}); ok {
+			coerceScalarsToSlices(m, f.Type)
+		}
+	}
+}
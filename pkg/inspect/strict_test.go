@@ -0,0 +1,36 @@
+package inspect
+
+import "testing"
+
+type strictTarget struct {
+	Enabled bool `json:"enabled"`
+}
+
+func TestConvertStrictReportsUnknownTopLevelFieldOnce(t *testing.T) {
+	raw := map[string]interface{}{"enabled": true, "unexpected": "field"}
+
+	var out strictTarget
+	diags, err := ConvertStrict(raw, &out, ConvertOptions{})
+	if err != nil {
+		t.Fatalf("ConvertStrict returned error: %v", err)
+	}
+	if len(diags) != 1 {
+		t.Fatalf("expected exactly one diagnostic for a single unknown field, found %v", diags)
+	}
+	if diags[0].Path != "/unexpected" {
+		t.Fatalf("expected the diagnostic to point at /unexpected, found %q", diags[0].Path)
+	}
+}
+
+func TestConvertStrictReportsTypeMismatchFromDecoder(t *testing.T) {
+	raw := map[string]interface{}{"enabled": "yes"}
+
+	var out strictTarget
+	diags, err := ConvertStrict(raw, &out, ConvertOptions{})
+	if err != nil {
+		t.Fatalf("ConvertStrict returned error: %v", err)
+	}
+	if len(diags) != 1 {
+		t.Fatalf("expected exactly one diagnostic for a type mismatch, found %v", diags)
+	}
+}
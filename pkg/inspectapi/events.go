@@ -0,0 +1,65 @@
+package inspectapi
+
+import "context"
+
+// eventBus fans reconciliation events out to whichever subscriptions are currently open for
+// the matching namespace/name. It's intentionally process-local: subscriptions don't survive
+// an operator restart, matching the existing reconcile loop's own lack of durable state.
+type eventBus struct {
+	subscribe_   chan subscribeRequest
+	unsubscribe_ chan subscribeRequest
+	publish_     chan ReconciliationEvent
+}
+
+type subscribeRequest struct {
+	namespace, name string
+	ch              chan ReconciliationEvent
+}
+
+func newEventBus() *eventBus {
+	b := &eventBus{
+		subscribe_:   make(chan subscribeRequest),
+		unsubscribe_: make(chan subscribeRequest),
+		publish_:     make(chan ReconciliationEvent, 16),
+	}
+	go b.run()
+	return b
+}
+
+func (b *eventBus) run() {
+	subs := map[subscribeRequest]bool{}
+	for {
+		select {
+		case req := <-b.subscribe_:
+			subs[req] = true
+		case req := <-b.unsubscribe_:
+			delete(subs, req)
+			close(req.ch)
+		case ev := <-b.publish_:
+			for req := range subs {
+				if req.namespace != ev.Namespace || req.name != ev.Name {
+					continue
+				}
+				select {
+				case req.ch <- ev:
+				default:
+					// Slow subscriber; drop the event rather than block reconciliation.
+				}
+			}
+		}
+	}
+}
+
+func (b *eventBus) publish(ev ReconciliationEvent) {
+	b.publish_ <- ev
+}
+
+func (b *eventBus) subscribe(ctx context.Context, namespace, name string) <-chan ReconciliationEvent {
+	req := subscribeRequest{namespace: namespace, name: name, ch: make(chan ReconciliationEvent, 4)}
+	b.subscribe_ <- req
+	go func() {
+		<-ctx.Done()
+		b.unsubscribe_ <- req
+	}()
+	return req.ch
+}
@@ -0,0 +1,44 @@
+package inspectapi
+
+// These mirror the types documented in contract.md. They're hand-written because this
+// subsystem is a fixed-field RPC dispatcher, not a real GraphQL server with a codegen step --
+// see server.go's dispatch.
+
+// SpinnakerConfig is the inspection-API projection of a SpinnakerService's merged config.
+type SpinnakerConfig struct {
+	Name      string
+	Namespace string
+	Profiles  []Profile
+}
+
+// Profile is one service's profile as stored in the SpinnakerService's config. The transformer
+// pipeline is not run on it, and secret references in it are returned unresolved.
+type Profile struct {
+	Service string
+	Content map[string]interface{}
+}
+
+// SecretRef describes one secret reference a service consumes, without its resolved value.
+type SecretRef struct {
+	Scheme string
+	Ref    string
+	AsFile bool
+}
+
+// GeneratedManifest is one rendered manifest produced by the transformer chain.
+type GeneratedManifest struct {
+	Name       string
+	Kind       string
+	APIVersion string
+	YAML       string
+}
+
+// ReconciliationEvent is a single step of a SpinnakerService reconcile, streamed to
+// in-process subscribers of Resolver.ReconciliationEvents (see contract.md for why this
+// isn't served over HTTP yet).
+type ReconciliationEvent struct {
+	Name        string
+	Namespace   string
+	Transformer string
+	Message     string
+}
@@ -0,0 +1,188 @@
+package inspectapi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/armory/spinnaker-operator/pkg/apis/spinnaker/interfaces"
+	"github.com/armory/spinnaker-operator/pkg/inspect"
+	"github.com/armory/spinnaker-operator/pkg/secrets"
+	"github.com/go-logr/logr"
+)
+
+// ServiceLookup resolves a SpinnakerService by namespace/name, the same version-agnostic way
+// the reconciler does (a SpinnakerService CR may be v1alpha1 or v1alpha2 under the hood).
+// It's injected rather than implemented here so pkg/inspectapi doesn't need to import the
+// reconciler package and the two stay free to evolve independently.
+type ServiceLookup func(ctx context.Context, namespace, name string) (interfaces.SpinnakerService, error)
+
+// ManifestLookup returns the manifests the transformer chain would currently generate for a
+// SpinnakerService, so reads are byte-identical to what the reconciler would apply.
+type ManifestLookup func(ctx context.Context, svc interfaces.SpinnakerService) ([]GeneratedManifest, error)
+
+// ReconcileTrigger asks the reconciler to reconcile the named SpinnakerService without
+// waiting for it to finish. It's injected for the same reason ServiceLookup is: this package
+// doesn't import the reconciler, so the caller wiring up Server is responsible for wiring
+// this to the real reconcile queue.
+type ReconcileTrigger func(ctx context.Context, namespace, name string) error
+
+// TransformerToggle enables or disables a named transformer for the next reconcile of a
+// SpinnakerService. Like ReconcileTrigger, it's injected rather than implemented here.
+type TransformerToggle func(ctx context.Context, namespace, name, transformer string, enabled bool) error
+
+// Resolver implements the reads and writes described in contract.md. It holds no
+// reconciliation state of its own: every read calls back into the reconciler's own code
+// paths via Lookup/Manifests, and every write calls back into it via Reconcile/ToggleTransformer
+// -- if those aren't wired up, the corresponding methods return an error rather than
+// reporting success for a write that never happened.
+type Resolver struct {
+	Lookup         ServiceLookup
+	Manifests      ManifestLookup
+	DoReconcile    ReconcileTrigger
+	SetTransformer TransformerToggle
+	MergeStrategy  inspect.MergeStrategy
+	Log            logr.Logger
+
+	events *eventBus
+}
+
+// NewResolver builds a Resolver ready to be served. MergeStrategy defaults to
+// inspect.DefaultMergeStrategy when zero-valued. reconcile and setTransformer may be nil,
+// in which case the corresponding write methods return an error instead of acting.
+func NewResolver(lookup ServiceLookup, manifests ManifestLookup, reconcile ReconcileTrigger, setTransformer TransformerToggle, log logr.Logger) *Resolver {
+	return &Resolver{
+		Lookup:         lookup,
+		Manifests:      manifests,
+		DoReconcile:    reconcile,
+		SetTransformer: setTransformer,
+		MergeStrategy:  inspect.DefaultMergeStrategy,
+		Log:            log,
+		events:         newEventBus(),
+	}
+}
+
+func (r *Resolver) SpinnakerConfig(ctx context.Context, namespace, name string) (*SpinnakerConfig, error) {
+	svc, err := r.Lookup(ctx, namespace, name)
+	if err != nil {
+		return nil, fmt.Errorf("looking up %s/%s: %v", namespace, name, err)
+	}
+	cfg := svc.GetSpinnakerConfig()
+	out := &SpinnakerConfig{Name: name, Namespace: namespace}
+	for service, p := range cfg.Profiles {
+		redacted, err := r.redact(ctx, namespace, p)
+		if err != nil {
+			return nil, err
+		}
+		out.Profiles = append(out.Profiles, Profile{Service: service, Content: redacted})
+	}
+	return out, nil
+}
+
+func (r *Resolver) Profile(ctx context.Context, namespace, name, service string) (*Profile, error) {
+	svc, err := r.Lookup(ctx, namespace, name)
+	if err != nil {
+		return nil, fmt.Errorf("looking up %s/%s: %v", namespace, name, err)
+	}
+	cfg := svc.GetSpinnakerConfig()
+	p, ok := cfg.Profiles[service]
+	if !ok {
+		return nil, nil
+	}
+	redacted, err := r.redact(ctx, namespace, p)
+	if err != nil {
+		return nil, err
+	}
+	return &Profile{Service: service, Content: redacted}, nil
+}
+
+func (r *Resolver) SecretRefs(ctx context.Context, namespace, name string) ([]SecretRef, error) {
+	svc, err := r.Lookup(ctx, namespace, name)
+	if err != nil {
+		return nil, fmt.Errorf("looking up %s/%s: %v", namespace, name, err)
+	}
+	var refs []SecretRef
+	for _, p := range svc.GetSpinnakerConfig().Profiles {
+		_, _, audit, err := secrets.Resolve(ctx, p, secrets.WithDryRun(), secrets.WithNamespace(namespace))
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range audit {
+			refs = append(refs, SecretRef{Scheme: a.Scheme, Ref: a.Ref, AsFile: a.AsFile})
+		}
+	}
+	return refs, nil
+}
+
+func (r *Resolver) GeneratedManifests(ctx context.Context, namespace, name string) ([]GeneratedManifest, error) {
+	svc, err := r.Lookup(ctx, namespace, name)
+	if err != nil {
+		return nil, fmt.Errorf("looking up %s/%s: %v", namespace, name, err)
+	}
+	return r.Manifests(ctx, svc)
+}
+
+// Reconcile requests a reconcile of the named SpinnakerService via DoReconcile, without
+// waiting for it to finish; progress can be followed via ReconciliationEvents. Returns an
+// error if DoReconcile wasn't wired up rather than reporting success for a reconcile that
+// never actually happened.
+func (r *Resolver) Reconcile(ctx context.Context, namespace, name string) (bool, error) {
+	if r.DoReconcile == nil {
+		return false, fmt.Errorf("reconcile trigger not configured")
+	}
+	if err := r.DoReconcile(ctx, namespace, name); err != nil {
+		return false, fmt.Errorf("reconciling %s/%s: %v", namespace, name, err)
+	}
+	r.events.publish(ReconciliationEvent{
+		Namespace: namespace,
+		Name:      name,
+		Message:   "reconcile requested via inspection API",
+	})
+	return true, nil
+}
+
+// RotateSecretRef evicts the cached value for a single ref so the next reconcile re-fetches
+// it from its backend, without needing to restart the operator or wait out the TTL.
+func (r *Resolver) RotateSecretRef(ctx context.Context, namespace, name, ref string) (bool, error) {
+	secrets.Evict(ref)
+	r.events.publish(ReconciliationEvent{
+		Namespace: namespace,
+		Name:      name,
+		Message:   fmt.Sprintf("secret ref %q rotated", ref),
+	})
+	return true, nil
+}
+
+// ToggleTransformer enables or disables a named transformer for the next reconcile of the
+// named SpinnakerService via SetTransformer. Returns an error if SetTransformer wasn't wired
+// up rather than reporting success for a toggle that was never enforced.
+func (r *Resolver) ToggleTransformer(ctx context.Context, namespace, name, transformer string, enabled bool) (bool, error) {
+	if r.SetTransformer == nil {
+		return false, fmt.Errorf("transformer toggle not configured")
+	}
+	if err := r.SetTransformer(ctx, namespace, name, transformer, enabled); err != nil {
+		return false, fmt.Errorf("toggling transformer %q for %s/%s: %v", transformer, namespace, name, err)
+	}
+	r.events.publish(ReconciliationEvent{
+		Namespace:   namespace,
+		Name:        name,
+		Transformer: transformer,
+		Message:     fmt.Sprintf("transformer %q toggled enabled=%v", transformer, enabled),
+	})
+	return true, nil
+}
+
+func (r *Resolver) ReconciliationEvents(ctx context.Context, namespace, name string) <-chan ReconciliationEvent {
+	return r.events.subscribe(ctx, namespace, name)
+}
+
+func (r *Resolver) redact(ctx context.Context, namespace string, p interfaces.FreeForm) (map[string]interface{}, error) {
+	redacted, _, _, err := secrets.Resolve(ctx, p, secrets.WithDryRun(), secrets.WithNamespace(namespace))
+	if err != nil {
+		return nil, err
+	}
+	m, ok := redacted.(interfaces.FreeForm)
+	if !ok {
+		return nil, fmt.Errorf("expected a profile to redact to a map, found %T", redacted)
+	}
+	return map[string]interface{}(m), nil
+}
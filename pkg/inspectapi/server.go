@@ -0,0 +1,111 @@
+package inspectapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-logr/logr"
+)
+
+// Authorizer authorizes a single inbound request before dispatch runs any query or write --
+// every query and write described in contract.md reaches an arbitrary namespace/name the
+// caller supplies, so without one this is an unauthenticated cluster-wide read/write surface.
+// Implementations might check a bearer token, an mTLS client certificate, or a SubjectAccessReview
+// against the caller's identity; that decision belongs to whatever binary wires Server up; this
+// package only enforces that one has been provided. Return a non-nil error to reject the
+// request -- ServeHTTP reports it as 401 Unauthorized without running dispatch.
+type Authorizer func(r *http.Request) error
+
+// Server serves the RPC-style API described in contract.md. It's deliberately minimal: a
+// single POST endpoint accepting {query, variables}, where query is a literal top-level field
+// name, and dispatching to the matching Resolver method. This is plain JSON-RPC-style
+// dispatch, not a GraphQL query executor: arbitrary documents, aliases, fragments, and nested
+// selection sets aren't supported, and reconciliationEvents isn't served here at all -- it has
+// no HTTP transport yet. Package inspectapi was previously named graphql and described this
+// as a GraphQL API; that claim didn't match what dispatch actually does and has been dropped.
+//
+// Nothing in this tree registers Server's ServeHTTP with a manager or http.Server yet -- there
+// is no main.go/manager-setup package here to wire a --enable-inspect-api flag into. A caller
+// that does add that wiring must also supply a real Authorizer; NewServer refuses a nil one so
+// that can't be forgotten silently.
+type Server struct {
+	resolver *Resolver
+	authz    Authorizer
+	log      logr.Logger
+}
+
+// NewServer wraps resolver for serving, gated by authz on every request. authz must not be
+// nil: there's no safe default authorization policy for a handler that can trigger a reconcile
+// or read secret references for any namespace/name a caller names.
+func NewServer(resolver *Resolver, authz Authorizer, log logr.Logger) (*Server, error) {
+	if authz == nil {
+		return nil, fmt.Errorf("inspectapi.NewServer requires a non-nil Authorizer")
+	}
+	return &Server{resolver: resolver, authz: authz, log: log}, nil
+}
+
+type request struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+// ServeHTTP handles POST /inspect. req.Query is matched as a literal top-level field name,
+// not parsed as a GraphQL document; see dispatch for the supported set.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "inspect endpoint only accepts POST", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := s.authz(r); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	var req request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	data, err := s.dispatch(r.Context(), req)
+	w.Header().Set("Content-Type", "application/json")
+	if err != nil {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"errors": []map[string]string{{"message": err.Error()}},
+		})
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+}
+
+func (s *Server) dispatch(ctx context.Context, req request) (interface{}, error) {
+	namespace, _ := req.Variables["namespace"].(string)
+	name, _ := req.Variables["name"].(string)
+
+	switch req.Query {
+	case "spinnakerConfig":
+		return s.resolver.SpinnakerConfig(ctx, namespace, name)
+	case "profile":
+		service, _ := req.Variables["service"].(string)
+		return s.resolver.Profile(ctx, namespace, name, service)
+	case "secretRefs":
+		return s.resolver.SecretRefs(ctx, namespace, name)
+	case "generatedManifests":
+		return s.resolver.GeneratedManifests(ctx, namespace, name)
+	case "reconcile":
+		return s.resolver.Reconcile(ctx, namespace, name)
+	case "rotateSecretRef":
+		ref, _ := req.Variables["ref"].(string)
+		return s.resolver.RotateSecretRef(ctx, namespace, name, ref)
+	case "toggleTransformer":
+		transformer, _ := req.Variables["transformer"].(string)
+		enabled, _ := req.Variables["enabled"].(bool)
+		return s.resolver.ToggleTransformer(ctx, namespace, name, transformer, enabled)
+	default:
+		return nil, errUnknownField(req.Query)
+	}
+}
+
+type errUnknownField string
+
+func (e errUnknownField) Error() string { return "unknown query " + string(e) }
@@ -0,0 +1,52 @@
+package inspectapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+)
+
+func TestNewServerRejectsNilAuthorizer(t *testing.T) {
+	if _, err := NewServer(&Resolver{}, nil, logr.Discard()); err == nil {
+		t.Fatal("expected NewServer to refuse a nil Authorizer")
+	}
+}
+
+func TestServeHTTPRejectsRequestWhenAuthorizerFails(t *testing.T) {
+	s, err := NewServer(&Resolver{}, func(r *http.Request) error {
+		return errors.New("no credentials presented")
+	}, logr.Discard())
+	if err != nil {
+		t.Fatalf("NewServer returned error: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/inspect", strings.NewReader(`{"query":"spinnakerConfig"}`))
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status %d, found %d", http.StatusUnauthorized, rec.Code)
+	}
+}
+
+func TestServeHTTPDispatchesWhenAuthorizerSucceeds(t *testing.T) {
+	s, err := NewServer(&Resolver{}, func(r *http.Request) error { return nil }, logr.Discard())
+	if err != nil {
+		t.Fatalf("NewServer returned error: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/inspect", strings.NewReader(`{"query":"unknownField"}`))
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, found %d", http.StatusOK, rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "unknown query") {
+		t.Fatalf("expected dispatch to run and report an unknown query, found %q", rec.Body.String())
+	}
+}
@@ -0,0 +1,95 @@
+package schema
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+//go:embed assets/*.json
+var embeddedAssets embed.FS
+
+// unversionedKey is used for schemas shipped in the binary that aren't tied to a specific
+// BOM version; a version-specific schema pulled in via RefreshFromBOM always takes
+// precedence over it once one has been registered.
+const unversionedKey = "unversioned"
+
+// Registry holds JSON Schemas for each Spinnaker service, optionally keyed by BOM version, so
+// a profile can be validated against the schema that actually shipped with the version the
+// SpinnakerService CR requested once that plumbing is available; until then, lookups fall
+// back to the unversioned schema embedded at build time.
+type Registry struct {
+	mu      sync.RWMutex
+	schemas map[string]*Schema
+}
+
+// NewRegistry loads every schema embedded at build time under assets/.
+func NewRegistry() (*Registry, error) {
+	r := &Registry{schemas: map[string]*Schema{}}
+	entries, err := embeddedAssets.ReadDir("assets")
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		b, err := embeddedAssets.ReadFile("assets/" + e.Name())
+		if err != nil {
+			return nil, fmt.Errorf("reading embedded schema %s: %v", e.Name(), err)
+		}
+		var s Schema
+		if err := json.Unmarshal(b, &s); err != nil {
+			return nil, fmt.Errorf("parsing embedded schema %s: %v", e.Name(), err)
+		}
+		service := strings.TrimSuffix(e.Name(), ".json")
+		r.schemas[key(service, unversionedKey)] = &s
+	}
+	return r, nil
+}
+
+// Get returns the schema registered for service, preferring one registered for the given
+// version if present, falling back to the unversioned schema embedded at build time. ok is
+// false when no schema is known at all, in which case callers should skip validation rather
+// than fail closed, since not every service ships a schema yet.
+func (r *Registry) Get(service, version string) (*Schema, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if version != "" {
+		if s, ok := r.schemas[key(service, version)]; ok {
+			return s, true
+		}
+	}
+	s, ok := r.schemas[key(service, unversionedKey)]
+	return s, ok
+}
+
+// Put registers or replaces the schema for service@version.
+func (r *Registry) Put(service, version string, s *Schema) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.schemas[key(service, version)] = s
+}
+
+// Fetcher fetches a service's JSON Schema for a specific BOM version, e.g. from the artifact
+// repository the BOM points at for that version.
+type Fetcher func(ctx context.Context, service, version string) ([]byte, error)
+
+// RefreshFromBOM fetches and registers the schema for service@version using fetch, so newly
+// released Spinnaker versions get schema validation without an operator rebuild.
+func (r *Registry) RefreshFromBOM(ctx context.Context, service, version string, fetch Fetcher) error {
+	b, err := fetch(ctx, service, version)
+	if err != nil {
+		return fmt.Errorf("fetching schema for %s@%s: %v", service, version, err)
+	}
+	var s Schema
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("parsing schema for %s@%s: %v", service, version, err)
+	}
+	r.Put(service, version, &s)
+	return nil
+}
+
+func key(service, version string) string {
+	return service + "@" + version
+}
@@ -0,0 +1,100 @@
+// Package schema validates merged SpinnakerConfig profiles against JSON Schemas shipped per
+// Spinnaker service, so mistakes surface as a clear, per-field diagnostic at transform time
+// instead of failing late and opaquely deep in reconciliation.
+package schema
+
+import (
+	"fmt"
+
+	"github.com/armory/spinnaker-operator/pkg/inspect"
+)
+
+// Schema is a practical subset of JSON Schema (draft-07): enough to validate the shape of a
+// Halyard profile -- types, required properties, nested objects and arrays -- without pulling
+// in a full schema validator dependency.
+type Schema struct {
+	Type                 string             `json:"type,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+}
+
+// Validate checks profile against s and returns every problem found, each located by a JSON
+// Pointer path, rather than stopping at the first. A nil return means profile is valid.
+func Validate(s *Schema, profile map[string]interface{}) []inspect.Diagnostic {
+	return validateAt(s, profile, "")
+}
+
+func validateAt(s *Schema, value interface{}, path string) []inspect.Diagnostic {
+	if s == nil {
+		return nil
+	}
+	var diags []inspect.Diagnostic
+
+	if s.Type != "" && !matchesType(s.Type, value) {
+		return append(diags, inspect.Diagnostic{
+			Path:    pointerOrRoot(path),
+			Message: fmt.Sprintf("expected type %q, found %T", s.Type, value),
+		})
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, req := range s.Required {
+			if _, ok := v[req]; !ok {
+				diags = append(diags, inspect.Diagnostic{
+					Path:    pointerOrRoot(path),
+					Message: fmt.Sprintf("missing required property %q", req),
+				})
+			}
+		}
+		for k, pv := range v {
+			ps, known := s.Properties[k]
+			if !known {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					diags = append(diags, inspect.Diagnostic{
+						Path:    path + "/" + k,
+						Message: fmt.Sprintf("unknown property %q", k),
+					})
+				}
+				continue
+			}
+			diags = append(diags, validateAt(ps, pv, path+"/"+k)...)
+		}
+	case []interface{}:
+		for i, item := range v {
+			diags = append(diags, validateAt(s.Items, item, fmt.Sprintf("%s/%d", path, i))...)
+		}
+	}
+	return diags
+}
+
+func matchesType(t string, v interface{}) bool {
+	switch t {
+	case "object":
+		_, ok := v.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := v.([]interface{})
+		return ok
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "number", "integer":
+		_, ok := v.(float64)
+		return ok
+	default:
+		return true
+	}
+}
+
+func pointerOrRoot(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return path
+}
@@ -0,0 +1,94 @@
+package schema
+
+import "testing"
+
+func TestValidateAcceptsMatchingProfile(t *testing.T) {
+	s := &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"archaius": {
+				Type: "object",
+				Properties: map[string]*Schema{
+					"enabled": {Type: "boolean"},
+				},
+				AdditionalProperties: boolPtr(false),
+			},
+		},
+	}
+	profile := map[string]interface{}{
+		"archaius": map[string]interface{}{"enabled": true},
+	}
+
+	if diags := Validate(s, profile); len(diags) != 0 {
+		t.Fatalf("expected no diagnostics for a matching profile, found %v", diags)
+	}
+}
+
+func TestValidateReportsTypeMismatch(t *testing.T) {
+	s := &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"archaius": {
+				Type: "object",
+				Properties: map[string]*Schema{
+					"enabled": {Type: "boolean"},
+				},
+			},
+		},
+	}
+	profile := map[string]interface{}{
+		"archaius": map[string]interface{}{"enabled": "yes"},
+	}
+
+	diags := Validate(s, profile)
+	if len(diags) != 1 {
+		t.Fatalf("expected one diagnostic for a type mismatch, found %v", diags)
+	}
+	if diags[0].Path != "/archaius/enabled" {
+		t.Fatalf("expected the diagnostic to point at /archaius/enabled, found %q", diags[0].Path)
+	}
+}
+
+func TestValidateReportsMissingRequiredProperty(t *testing.T) {
+	s := &Schema{
+		Type:     "object",
+		Required: []string{"archaius"},
+	}
+
+	diags := Validate(s, map[string]interface{}{})
+	if len(diags) != 1 {
+		t.Fatalf("expected one diagnostic for a missing required property, found %v", diags)
+	}
+}
+
+func TestValidateRejectsUnknownPropertyWhenClosed(t *testing.T) {
+	s := &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"enabled": {Type: "boolean"},
+		},
+		AdditionalProperties: boolPtr(false),
+	}
+	profile := map[string]interface{}{"enabled": true, "unexpected": "field"}
+
+	diags := Validate(s, profile)
+	if len(diags) != 1 {
+		t.Fatalf("expected one diagnostic for an unknown property, found %v", diags)
+	}
+}
+
+func TestValidateAllowsUnknownPropertyWhenOpen(t *testing.T) {
+	s := &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"enabled": {Type: "boolean"},
+		},
+	}
+	profile := map[string]interface{}{"enabled": true, "unmodeled": "field"}
+
+	if diags := Validate(s, profile); len(diags) != 0 {
+		t.Fatalf("expected no diagnostics when additionalProperties is unset (open), found %v", diags)
+	}
+}
+
+func boolPtr(b bool) *bool { return &b }
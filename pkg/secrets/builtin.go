@@ -0,0 +1,193 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	vaultapi "github.com/hashicorp/vault/api"
+	secretmanagerpb "google.golang.org/genproto/googleapis/cloud/secretmanager/v1"
+
+	secretmanager "cloud.google.com/go/secretmanager/apiv1"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RegisterDefaults registers the built-in resolvers (k8s, vault, gcpsm, awssm, encrypted)
+// with the package-level registry. c is used by the k8s resolver to read Secret objects
+// through the same client the operator already uses to reconcile.
+func RegisterDefaults(c client.Client, vault *vaultapi.Client, gcp *secretmanager.Client, aws_ *secretsmanager.Client) {
+	Register(&k8sResolver{client: c})
+	Register(&vaultResolver{client: vault})
+	Register(&gcpsmResolver{client: gcp})
+	Register(&awssmResolver{client: aws_})
+	Register(&encryptedResolver{})
+}
+
+// k8sResolver resolves "k8s://namespace/name/key" references against Kubernetes Secret
+// objects. When the call carries a namespace via secrets.WithNamespace (see Resolve), a ref
+// naming any other namespace is rejected: without this, any caller able to edit a
+// SpinnakerService CR could read an arbitrary Secret in the cluster -- e.g.
+// "k8s://kube-system/some-secret/token" -- through the operator's own client.
+type k8sResolver struct {
+	client client.Client
+}
+
+func (r *k8sResolver) Scheme() string { return "k8s" }
+
+func (r *k8sResolver) Resolve(ctx context.Context, ref string) (string, map[string][]byte, error) {
+	if r.client == nil {
+		return "", nil, fmt.Errorf("k8s:// secret resolver is not configured")
+	}
+	parts := strings.SplitN(ref, "/", 3)
+	if len(parts) != 3 {
+		return "", nil, fmt.Errorf("expected k8s://namespace/name/key, found %q", ref)
+	}
+	ns, name, key := parts[0], parts[1], parts[2]
+	if allowed, ok := NamespaceFromContext(ctx); ok && ns != allowed {
+		return "", nil, fmt.Errorf("k8s:// secret ref must be in namespace %q, found %q", allowed, ns)
+	}
+	s := &corev1.Secret{}
+	if err := r.client.Get(ctx, types.NamespacedName{Namespace: ns, Name: name}, s); err != nil {
+		return "", nil, fmt.Errorf("fetching secret %s/%s: %v", ns, name, err)
+	}
+	v, ok := s.Data[key]
+	if !ok {
+		return "", nil, fmt.Errorf("secret %s/%s has no key %q", ns, name, key)
+	}
+	return string(v), nil, nil
+}
+
+// vaultResolver resolves "vault://path#field" references against HashiCorp Vault, using
+// whatever auth the client was configured with (token or Kubernetes auth).
+type vaultResolver struct {
+	client *vaultapi.Client
+}
+
+func (r *vaultResolver) Scheme() string { return "vault" }
+
+func (r *vaultResolver) Resolve(ctx context.Context, ref string) (string, map[string][]byte, error) {
+	if r.client == nil {
+		return "", nil, fmt.Errorf("vault:// secret resolver is not configured")
+	}
+	path, field, ok := cutLast(ref, "#")
+	if !ok {
+		return "", nil, fmt.Errorf("expected vault://path#field, found %q", ref)
+	}
+	secret, err := r.client.Logical().ReadWithContext(ctx, path)
+	if err != nil {
+		return "", nil, fmt.Errorf("reading vault secret %q: %v", path, err)
+	}
+	if secret == nil {
+		return "", nil, fmt.Errorf("no vault secret found at %q", path)
+	}
+	v, ok := secret.Data[field]
+	if !ok {
+		return "", nil, fmt.Errorf("vault secret %q has no field %q", path, field)
+	}
+	return fmt.Sprintf("%v", v), nil, nil
+}
+
+// gcpsmResolver resolves "gcpsm://project/name/version" references against GCP Secret Manager.
+type gcpsmResolver struct {
+	client *secretmanager.Client
+}
+
+func (r *gcpsmResolver) Scheme() string { return "gcpsm" }
+
+func (r *gcpsmResolver) Resolve(ctx context.Context, ref string) (string, map[string][]byte, error) {
+	if r.client == nil {
+		return "", nil, fmt.Errorf("gcpsm:// secret resolver is not configured")
+	}
+	parts := strings.SplitN(ref, "/", 3)
+	if len(parts) != 3 {
+		return "", nil, fmt.Errorf("expected gcpsm://project/name/version, found %q", ref)
+	}
+	project, name, version := parts[0], parts[1], parts[2]
+	req := &secretmanagerpb.AccessSecretVersionRequest{
+		Name: fmt.Sprintf("projects/%s/secrets/%s/versions/%s", project, name, version),
+	}
+	resp, err := r.client.AccessSecretVersion(ctx, req)
+	if err != nil {
+		return "", nil, fmt.Errorf("accessing gcp secret %s/%s@%s: %v", project, name, version, err)
+	}
+	return string(resp.Payload.Data), nil, nil
+}
+
+// awssmResolver resolves "awssm://region/name" references against AWS Secrets Manager.
+type awssmResolver struct {
+	client *secretsmanager.Client
+}
+
+func (r *awssmResolver) Scheme() string { return "awssm" }
+
+func (r *awssmResolver) Resolve(ctx context.Context, ref string) (string, map[string][]byte, error) {
+	if r.client == nil {
+		return "", nil, fmt.Errorf("awssm:// secret resolver is not configured")
+	}
+	region, name, ok := cutFirst(ref, "/")
+	if !ok {
+		return "", nil, fmt.Errorf("expected awssm://region/name, found %q", ref)
+	}
+	out, err := r.client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(name),
+	}, func(o *secretsmanager.Options) { o.Region = region })
+	if err != nil {
+		return "", nil, fmt.Errorf("fetching aws secret %s in %s: %v", name, region, err)
+	}
+	if out.SecretString == nil {
+		return "", nil, fmt.Errorf("aws secret %s in %s has no string value", name, region)
+	}
+	return *out.SecretString, nil, nil
+}
+
+// encryptedResolver resolves the legacy Halyard "encrypted:<type>!k:v!k:v..." syntax,
+// kept for configs migrating from Halyard-managed secrets.
+type encryptedResolver struct{}
+
+func (r *encryptedResolver) Scheme() string { return "encrypted" }
+
+func (r *encryptedResolver) Resolve(ctx context.Context, ref string) (string, map[string][]byte, error) {
+	segments := strings.Split(ref, "!")
+	if len(segments) == 0 {
+		return "", nil, fmt.Errorf("invalid encrypted secret reference %q", ref)
+	}
+	params := map[string]string{}
+	for _, s := range segments[1:] {
+		k, v, ok := cutFirst(s, ":")
+		if !ok {
+			return "", nil, fmt.Errorf("invalid encrypted secret parameter %q", s)
+		}
+		params[k] = v
+	}
+	if isFile, _ := strconv.ParseBool(params["isFile"]); isFile {
+		return "", nil, fmt.Errorf("file-backed encrypted: secrets are not yet supported by the resolver")
+	}
+	v, ok := params["n"]
+	if !ok {
+		return "", nil, fmt.Errorf("encrypted secret %q missing value parameter %q", ref, "n")
+	}
+	return v, nil, nil
+}
+
+func cutFirst(s, sep string) (before, after string, ok bool) {
+	i := strings.Index(s, sep)
+	if i < 0 {
+		return "", "", false
+	}
+	return s[:i], s[i+len(sep):], true
+}
+
+func cutLast(s, sep string) (before, after string, ok bool) {
+	i := strings.LastIndex(s, sep)
+	if i < 0 {
+		return "", "", false
+	}
+	return s[:i], s[i+len(sep):], true
+}
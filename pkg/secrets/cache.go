@@ -0,0 +1,60 @@
+package secrets
+
+import (
+	"sync"
+	"time"
+)
+
+type cacheEntry struct {
+	value     string
+	files     map[string][]byte
+	expiresAt time.Time
+}
+
+// cache is a simple TTL cache for resolved secret references, keyed by the full ref string
+// (which already includes any version/field suffix, e.g. "vault://path#field").
+type cache struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	now     func() time.Time
+}
+
+// newCache returns an empty cache. now defaults to time.Now and is only overridden in tests.
+func newCache() *cache {
+	return &cache{entries: map[string]cacheEntry{}, now: time.Now}
+}
+
+var defaultCache = newCache()
+
+func (c *cache) get(ref string) (cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[ref]
+	if !ok {
+		return cacheEntry{}, false
+	}
+	if c.now().After(e.expiresAt) {
+		delete(c.entries, ref)
+		return cacheEntry{}, false
+	}
+	return e, true
+}
+
+func (c *cache) put(ref string, e cacheEntry, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e.expiresAt = c.now().Add(ttl)
+	c.entries[ref] = e
+}
+
+func (c *cache) evict(ref string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, ref)
+}
+
+// Evict removes ref from the default resolution cache, so the next Resolve call for it
+// re-fetches from the backend instead of returning a stale cached value.
+func Evict(ref string) {
+	defaultCache.evict(ref)
+}
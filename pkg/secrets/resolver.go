@@ -0,0 +1,205 @@
+// Package secrets turns inspect.InspectStrings into the front end for a pluggable secret
+// reference resolver: any string field in a SpinnakerConfig (profiles, service settings,
+// files) that matches a registered URI scheme is resolved at transform time instead of being
+// handled by ad-hoc string substitution in each transformer.
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/armory/spinnaker-operator/pkg/inspect"
+	"github.com/go-logr/logr"
+)
+
+// Resolver resolves a reference string for a single URI scheme into either an inline value
+// or file contents to be mounted. Implementations are registered once at startup via Register.
+type Resolver interface {
+	// Scheme returns the URI scheme this resolver handles, e.g. "vault" for "vault://...".
+	Scheme() string
+	// Resolve returns either an inline value or, for refs that back onto file-shaped secrets,
+	// the file contents keyed by the path they should be materialized at. Exactly one of the
+	// two should be populated.
+	Resolve(ctx context.Context, ref string) (value string, files map[string][]byte, err error)
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Resolver{}
+)
+
+// Register adds r to the set of known resolvers, keyed by r.Scheme(). Registering a scheme
+// twice replaces the previous resolver, which is mainly useful for tests.
+func Register(r Resolver) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[r.Scheme()] = r
+}
+
+func lookup(scheme string) (Resolver, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	r, ok := registry[scheme]
+	return r, ok
+}
+
+// AuditEntry records a single secret reference a service consumed, for structured audit
+// logging of what was read while rendering a given SpinnakerService.
+type AuditEntry struct {
+	Scheme string
+	Ref    string
+	AsFile bool
+	DryRun bool
+}
+
+// Resolve walks every string field reachable from i and resolves any that look like
+// "<scheme>://..." references, or the legacy "encrypted:..." form, using the resolver
+// registered for that scheme. Refs that resolve to file bytes are materialized under files, with the string
+// field replaced by the path they'll be mounted at; scalar refs are inlined in place.
+//
+// Unrecognized schemes (no resolver registered) are left untouched, since not every
+// colon-containing string is a secret reference.
+func Resolve(ctx context.Context, i interface{}, opts ...Option) (resolved interface{}, files map[string][]byte, audit []AuditEntry, err error) {
+	o := buildOptions(opts)
+	files = map[string][]byte{}
+
+	handler := func(val string) (string, error) {
+		scheme, ref, ok := splitRef(val)
+		if !ok {
+			return val, nil
+		}
+		r, ok := lookup(scheme)
+		if !ok {
+			return val, nil
+		}
+
+		if o.dryRun {
+			audit = append(audit, AuditEntry{Scheme: scheme, Ref: val, DryRun: true})
+			return val, nil
+		}
+
+		if cached, ok := o.cache.get(val); ok {
+			audit = append(audit, AuditEntry{Scheme: scheme, Ref: val, AsFile: len(cached.files) > 0})
+			for p, b := range cached.files {
+				files[p] = b
+			}
+			return cached.value, nil
+		}
+
+		value, refFiles, err := r.Resolve(withNamespace(ctx, o.namespace), ref)
+		if err != nil {
+			return val, fmt.Errorf("resolving secret ref %q: %v", val, err)
+		}
+
+		result := value
+		if len(refFiles) > 0 {
+			for p, b := range refFiles {
+				files[p] = b
+			}
+			// A file-backed ref is inlined as the path it will be mounted at, so the caller's
+			// original string field just becomes a regular file path.
+			result = filePathFor(val, refFiles)
+		}
+
+		o.cache.put(val, cacheEntry{value: result, files: refFiles}, o.ttl)
+		if o.log != nil {
+			o.log.Info("resolved secret reference", "scheme", scheme, "asFile", len(refFiles) > 0)
+		}
+		audit = append(audit, AuditEntry{Scheme: scheme, Ref: val, AsFile: len(refFiles) > 0})
+		return result, nil
+	}
+
+	resolved, err = inspect.InspectStrings(i, handler)
+	return resolved, files, audit, err
+}
+
+// Option configures a call to Resolve.
+type Option func(*options)
+
+type options struct {
+	dryRun    bool
+	log       logr.Logger
+	cache     *cache
+	ttl       time.Duration
+	namespace string
+}
+
+// WithDryRun reports which refs would be resolved without contacting any backend.
+func WithDryRun() Option {
+	return func(o *options) { o.dryRun = true }
+}
+
+// WithLogger attaches a logger used for structured audit logging of resolved references.
+func WithLogger(log logr.Logger) Option {
+	return func(o *options) { o.log = log }
+}
+
+// WithCache overrides the default resolution cache, keyed by ref, with a custom TTL.
+func WithCache(c *cache, ttl time.Duration) Option {
+	return func(o *options) {
+		o.cache = c
+		o.ttl = ttl
+	}
+}
+
+// WithNamespace scopes resolution to the given namespace: resolvers that read namespaced
+// objects (currently k8sResolver) reject a ref naming any other namespace instead of
+// resolving it. Callers rendering a specific SpinnakerService's config should always set
+// this to that service's own namespace -- leaving it unset lets a ref read from any
+// namespace the operator's client can reach, which is only appropriate for trusted,
+// operator-internal callers, not for resolving attacker-influenced CR content. A dry-run
+// Resolve never calls a resolver, so the namespace has no effect there.
+func WithNamespace(ns string) Option {
+	return func(o *options) { o.namespace = ns }
+}
+
+func buildOptions(opts []Option) *options {
+	o := &options{cache: defaultCache, ttl: 5 * time.Minute}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// splitRef splits a candidate reference string into scheme and remainder. It accepts both
+// "scheme://rest" (k8s://, vault://, gcpsm://, awssm://) and the legacy halyard
+// "scheme:rest" form used by "encrypted:".
+func splitRef(val string) (scheme, rest string, ok bool) {
+	if i := strings.Index(val, "://"); i > 0 {
+		return val[:i], val[i+3:], true
+	}
+	if i := strings.Index(val, ":"); i > 0 && val[:i] == "encrypted" {
+		return val[:i], val[i+1:], true
+	}
+	return "", "", false
+}
+
+func filePathFor(ref string, files map[string][]byte) string {
+	for p := range files {
+		return p
+	}
+	return ref
+}
+
+type namespaceCtxKey struct{}
+
+// withNamespace attaches the namespace a resolver should scope itself to (see WithNamespace)
+// to ctx, so Resolver implementations can enforce it without it being part of the Resolver
+// interface signature itself.
+func withNamespace(ctx context.Context, ns string) context.Context {
+	if ns == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, namespaceCtxKey{}, ns)
+}
+
+// NamespaceFromContext returns the namespace a Resolve call was scoped to via WithNamespace,
+// if any. Resolver implementations that read namespaced cluster objects should call this and
+// reject a ref naming a different namespace.
+func NamespaceFromContext(ctx context.Context) (string, bool) {
+	ns, ok := ctx.Value(namespaceCtxKey{}).(string)
+	return ns, ok
+}
@@ -0,0 +1,174 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeResolver struct {
+	scheme string
+	calls  int
+	value  string
+	files  map[string][]byte
+	err    error
+}
+
+func (r *fakeResolver) Scheme() string { return r.scheme }
+
+func (r *fakeResolver) Resolve(ctx context.Context, ref string) (string, map[string][]byte, error) {
+	r.calls++
+	return r.value, r.files, r.err
+}
+
+func TestResolveInlinesScalarRefsForRegisteredScheme(t *testing.T) {
+	r := &fakeResolver{scheme: "fake", value: "secret-value"}
+	Register(r)
+
+	profile := map[string]interface{}{"password": "fake://creds/password"}
+	resolved, files, audit, err := Resolve(context.Background(), profile, WithCache(newCache(), time.Minute))
+	if err != nil {
+		t.Fatalf("Resolve returned error: %v", err)
+	}
+	m := resolved.(map[string]interface{})
+	if m["password"] != "secret-value" {
+		t.Fatalf("expected password to be inlined, found %v", m["password"])
+	}
+	if len(files) != 0 {
+		t.Fatalf("expected no files for a scalar ref, found %v", files)
+	}
+	if len(audit) != 1 || audit[0].Scheme != "fake" {
+		t.Fatalf("expected one audit entry for scheme fake, found %v", audit)
+	}
+}
+
+func TestResolveLeavesUnrecognizedSchemesUntouched(t *testing.T) {
+	profile := map[string]interface{}{"note": "unregistered-scheme://some/path"}
+	resolved, _, audit, err := Resolve(context.Background(), profile, WithCache(newCache(), time.Minute))
+	if err != nil {
+		t.Fatalf("Resolve returned error: %v", err)
+	}
+	m := resolved.(map[string]interface{})
+	if m["note"] != "unregistered-scheme://some/path" {
+		t.Fatalf("expected a ref with an unregistered scheme to be left alone, found %v", m["note"])
+	}
+	if len(audit) != 0 {
+		t.Fatalf("expected no audit entries for a ref with an unregistered scheme, found %v", audit)
+	}
+}
+
+func TestResolveDryRunDoesNotContactBackend(t *testing.T) {
+	r := &fakeResolver{scheme: "fake2", value: "secret-value"}
+	Register(r)
+
+	profile := map[string]interface{}{"password": "fake2://creds/password"}
+	resolved, _, audit, err := Resolve(context.Background(), profile, WithDryRun())
+	if err != nil {
+		t.Fatalf("Resolve returned error: %v", err)
+	}
+	if r.calls != 0 {
+		t.Fatalf("expected dry-run to skip calling the resolver, called %d times", r.calls)
+	}
+	m := resolved.(map[string]interface{})
+	if m["password"] != "fake2://creds/password" {
+		t.Fatalf("expected dry-run to leave the ref string unresolved, found %v", m["password"])
+	}
+	if len(audit) != 1 || !audit[0].DryRun {
+		t.Fatalf("expected one dry-run audit entry, found %v", audit)
+	}
+}
+
+func TestResolveCachesByRefUntilTTLExpires(t *testing.T) {
+	r := &fakeResolver{scheme: "fake3", value: "secret-value"}
+	Register(r)
+
+	now := time.Now()
+	c := newCache()
+	c.now = func() time.Time { return now }
+	profile := map[string]interface{}{"password": "fake3://creds/password"}
+
+	if _, _, _, err := Resolve(context.Background(), profile, WithCache(c, time.Minute)); err != nil {
+		t.Fatalf("Resolve returned error: %v", err)
+	}
+	if _, _, _, err := Resolve(context.Background(), profile, WithCache(c, time.Minute)); err != nil {
+		t.Fatalf("Resolve returned error: %v", err)
+	}
+	if r.calls != 1 {
+		t.Fatalf("expected the resolver to be called once and the second Resolve to hit the cache, called %d times", r.calls)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, _, _, err := Resolve(context.Background(), profile, WithCache(c, time.Minute)); err != nil {
+		t.Fatalf("Resolve returned error: %v", err)
+	}
+	if r.calls != 2 {
+		t.Fatalf("expected the resolver to be called again once the cache entry expired, called %d times", r.calls)
+	}
+}
+
+func TestResolveWithNamespaceRejectsRefScopedToAnotherNamespace(t *testing.T) {
+	r := &namespacedResolver{scheme: "fake5"}
+	Register(r)
+
+	profile := map[string]interface{}{"password": "fake5://other-ns/name/key"}
+	if _, _, _, err := Resolve(context.Background(), profile, WithNamespace("team-a"), WithCache(newCache(), time.Minute)); err == nil {
+		t.Fatal("expected an error resolving a ref scoped to a different namespace")
+	}
+}
+
+func TestResolveWithNamespaceAllowsRefInOwnNamespace(t *testing.T) {
+	r := &namespacedResolver{scheme: "fake6"}
+	Register(r)
+
+	profile := map[string]interface{}{"password": "fake6://team-a/name/key"}
+	resolved, _, _, err := Resolve(context.Background(), profile, WithNamespace("team-a"), WithCache(newCache(), time.Minute))
+	if err != nil {
+		t.Fatalf("Resolve returned error: %v", err)
+	}
+	m := resolved.(map[string]interface{})
+	if m["password"] != "secret-value" {
+		t.Fatalf("expected password to be inlined, found %v", m["password"])
+	}
+}
+
+// namespacedResolver mimics k8sResolver's namespace-prefixed ref shape and enforcement, without
+// pulling in a Kubernetes client, so the WithNamespace contract can be tested at the Resolve
+// level independent of any one resolver implementation.
+type namespacedResolver struct {
+	scheme string
+}
+
+func (r *namespacedResolver) Scheme() string { return r.scheme }
+
+func (r *namespacedResolver) Resolve(ctx context.Context, ref string) (string, map[string][]byte, error) {
+	parts := strings.SplitN(ref, "/", 3)
+	ns := parts[0]
+	if allowed, ok := NamespaceFromContext(ctx); ok && ns != allowed {
+		return "", nil, fmt.Errorf("ref must be in namespace %q, found %q", allowed, ns)
+	}
+	return "secret-value", nil, nil
+}
+
+func TestResolveMaterializesFileBackedRefsAsPaths(t *testing.T) {
+	files := map[string][]byte{"/mnt/secrets/fake4/cert": []byte("cert-bytes")}
+	r := &fakeResolver{scheme: "fake4", files: files}
+	Register(r)
+
+	profile := map[string]interface{}{"certFile": "fake4://creds/cert"}
+	resolved, gotFiles, audit, err := Resolve(context.Background(), profile, WithCache(newCache(), time.Minute))
+	if err != nil {
+		t.Fatalf("Resolve returned error: %v", err)
+	}
+	m := resolved.(map[string]interface{})
+	if m["certFile"] != "/mnt/secrets/fake4/cert" {
+		t.Fatalf("expected the field to become the file's mount path, found %v", m["certFile"])
+	}
+	if len(gotFiles) != 1 {
+		t.Fatalf("expected one file to be returned, found %v", gotFiles)
+	}
+	if !audit[0].AsFile {
+		t.Fatalf("expected the audit entry to be marked AsFile, found %v", audit[0])
+	}
+}